@@ -20,8 +20,17 @@
 package notify
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -29,6 +38,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	elasticv8 "github.com/elastic/go-elasticsearch/v8"
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
 	"github.com/infracloudio/botkube/pkg/log"
@@ -41,17 +51,28 @@ const (
 	indexSuffixFormat = "02-01-2006"
 	// awsService for the AWS client to authenticate against
 	awsService = "es"
+	// minSupportedMajorVersion is the oldest Elasticsearch major version botkube will talk to
+	minSupportedMajorVersion = 5
+	// docType is the document type used on 7.x, which only allows a single type named "_doc"
+	docType = "_doc"
 )
 
 // ElasticSearch contains auth cred and index setting
 type ElasticSearch struct {
-	ELSClient   *elastic.Client
-	Server      string
-	Index       string
-	Shards      int
-	Replicas    int
-	Type        string
-	ClusterName string
+	ELSClient     *elastic.Client
+	ELS8Client    *elasticv8.Client
+	BulkProcessor *elastic.BulkProcessor
+	Server        string
+	Index         string
+	Shards        int
+	Replicas      int
+	Type          string
+	ClusterName   string
+	ServerVersion string
+	MajorVersion  int
+	UseILM        bool
+	WriteAlias    string
+	Formatter     DocumentFormatter
 }
 
 // NewElasticSearch returns new ElasticSearch object
@@ -59,6 +80,29 @@ func NewElasticSearch(c *config.Config) (Notifier, error) {
 	var elsClient *elastic.Client
 	var err error
 	var creds *credentials.Credentials
+
+	server := c.CommunicationsConfig.ElasticSearch.Server
+	if c.CommunicationsConfig.ElasticSearch.CloudID != "" {
+		server, err = decodeCloudID(c.CommunicationsConfig.ElasticSearch.CloudID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConfig, err := buildTLSConfig(c.CommunicationsConfig.ElasticSearch.TLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	httpClient := &http.Client{Transport: transport}
+	headers := authHeaders(c.CommunicationsConfig.ElasticSearch)
+
+	// signedClient is whichever http.Client callers should actually send requests through:
+	// the plain TLS-configured one, or (when AWS signing is on) one that SigV4-signs every
+	// request. Both the olivere client below and the ES8 client further down share it, so
+	// AWS-signed/bearer-authenticated requests work the same regardless of server version.
+	signedClient := httpClient
 	if c.CommunicationsConfig.ElasticSearch.AWSSigning.Enabled {
 		// Get credentials from environment variables and create the AWS Signature Version 4 signer
 		sess := session.Must(session.NewSession())
@@ -69,38 +113,279 @@ func NewElasticSearch(c *config.Config) (Notifier, error) {
 		}
 
 		signer := v4.NewSigner(creds)
-		awsClient, err := aws_signing_client.New(signer, nil, awsService, c.CommunicationsConfig.ElasticSearch.AWSSigning.AWSRegion)
-
-		if err != nil {
-			return nil, err
-		}
-		elsClient, err = elastic.NewClient(elastic.SetURL(c.CommunicationsConfig.ElasticSearch.Server), elastic.SetScheme("https"), elastic.SetHttpClient(awsClient), elastic.SetSniff(false), elastic.SetHealthcheck(false), elastic.SetGzip(false))
+		awsClient, err := aws_signing_client.New(signer, httpClient, awsService, c.CommunicationsConfig.ElasticSearch.AWSSigning.AWSRegion)
 		if err != nil {
 			return nil, err
 		}
+		signedClient = awsClient
+	}
+
+	clientOpts := []elastic.ClientOptionFunc{
+		elastic.SetURL(server),
+		elastic.SetSniff(false),
+		elastic.SetHealthcheck(false),
+		elastic.SetHttpClient(signedClient),
+	}
+	if len(headers) > 0 {
+		clientOpts = append(clientOpts, elastic.SetHeaders(headers))
+	}
+	if c.CommunicationsConfig.ElasticSearch.AWSSigning.Enabled {
+		clientOpts = append(clientOpts, elastic.SetScheme("https"), elastic.SetGzip(false))
 	} else {
-		// create elasticsearch client
-		elsClient, err = elastic.NewClient(
-			elastic.SetURL(c.CommunicationsConfig.ElasticSearch.Server),
+		clientOpts = append(clientOpts,
 			elastic.SetBasicAuth(c.CommunicationsConfig.ElasticSearch.Username, c.CommunicationsConfig.ElasticSearch.Password),
-			elastic.SetSniff(false),
-			elastic.SetHealthcheck(false),
 			elastic.SetGzip(true),
 		)
+	}
+	elsClient, err = elastic.NewClient(clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detect the server version so we know which indexing code path to use
+	pingResult, _, err := elsClient.Ping(server).Do(context.Background())
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to ping Elasticsearch server. Error:%s", err.Error()))
+		return nil, err
+	}
+	serverVersion := pingResult.Version.Number
+	majorVersion, err := parseMajorVersion(serverVersion)
+	if err != nil {
+		return nil, err
+	}
+	if majorVersion < minSupportedMajorVersion {
+		return nil, fmt.Errorf("unsupported Elasticsearch server version %s, minimum supported major version is %d", serverVersion, minSupportedMajorVersion)
+	}
+
+	docTypeToUse := c.CommunicationsConfig.ElasticSearch.Index.Type
+	ilmCfg := c.CommunicationsConfig.ElasticSearch.ILM
+	switch {
+	case majorVersion >= 8:
+		// ILM/rollover isn't implemented for the ES8 client path yet; fail fast instead of
+		// silently falling back to daily date-suffixed indices.
+		if ilmCfg.Enabled {
+			return nil, fmt.Errorf("ILM/rollover mode is not yet supported against Elasticsearch 8.x, server is running %s", serverVersion)
+		}
+		// 8.x removed mapping types entirely and dropped most of the legacy REST client surface,
+		// so we talk to it through the official go-elasticsearch transport instead of olivere/elastic.
+		esCfg := elasticv8.Config{
+			Addresses: []string{server},
+			Username:  c.CommunicationsConfig.ElasticSearch.Username,
+			Password:  c.CommunicationsConfig.ElasticSearch.Password,
+			CloudID:   c.CommunicationsConfig.ElasticSearch.CloudID,
+			// API-key/bearer auth and AWS SigV4 signing are both carried as plain headers/transport
+			// here instead of esCfg.APIKey, so they go through the same signedClient used below and
+			// never produce a second, conflicting Authorization header.
+			Header:    headers,
+			Transport: signedClient.Transport,
+		}
+		els8Client, err := elasticv8.NewClient(esCfg)
 		if err != nil {
 			return nil, err
 		}
+		return &ElasticSearch{
+			ELS8Client:    els8Client,
+			Index:         c.CommunicationsConfig.ElasticSearch.Index.Name,
+			Shards:        c.CommunicationsConfig.ElasticSearch.Index.Shards,
+			Replicas:      c.CommunicationsConfig.ElasticSearch.Index.Replicas,
+			ClusterName:   c.Settings.ClusterName,
+			ServerVersion: serverVersion,
+			MajorVersion:  majorVersion,
+			Formatter:     newDocumentFormatter(c.CommunicationsConfig.ElasticSearch.Format),
+		}, nil
+	case majorVersion == 7:
+		// Mapping types were removed in 7.x; "_doc" is the only type name still accepted.
+		docTypeToUse = docType
+	default:
+		// 5.x/6.x still support user-defined mapping types.
+		if docTypeToUse == "" {
+			docTypeToUse = docType
+		}
+		log.Warn(fmt.Sprintf("Elasticsearch server %s is running version %s, which is deprecated. Please upgrade to 7.x or later.", c.CommunicationsConfig.ElasticSearch.Server, serverVersion))
 	}
+
+	// Set up ILM/rollover before starting the bulk processor: both can fail, and failing here
+	// first means we never start (and then have to tear down) a processor we're about to discard.
+	writeAlias := ""
+	if ilmCfg.Enabled {
+		if majorVersion < 7 {
+			return nil, fmt.Errorf("ILM/rollover mode requires Elasticsearch 7.x or later, server is running %s", serverVersion)
+		}
+		writeAlias = ilmCfg.WriteAlias
+		if writeAlias == "" {
+			writeAlias = c.CommunicationsConfig.ElasticSearch.Index.Name + "-write"
+		}
+		if err := ensureRollover(context.Background(), elsClient, ilmCfg, writeAlias, c.CommunicationsConfig.ElasticSearch.Format); err != nil {
+			log.Error(fmt.Sprintf("Failed to set up Elasticsearch ILM/rollover. Error:%s", err.Error()))
+			return nil, err
+		}
+	}
+
+	bulkProcessor, err := newBulkProcessor(elsClient, c.CommunicationsConfig.ElasticSearch)
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to start Elasticsearch bulk processor. Error:%s", err.Error()))
+		return nil, err
+	}
+
 	return &ElasticSearch{
-		ELSClient:   elsClient,
-		Index:       c.CommunicationsConfig.ElasticSearch.Index.Name,
-		Type:        c.CommunicationsConfig.ElasticSearch.Index.Type,
-		Shards:      c.CommunicationsConfig.ElasticSearch.Index.Shards,
-		Replicas:    c.CommunicationsConfig.ElasticSearch.Index.Replicas,
-		ClusterName: c.Settings.ClusterName,
+		ELSClient:     elsClient,
+		BulkProcessor: bulkProcessor,
+		Index:         c.CommunicationsConfig.ElasticSearch.Index.Name,
+		Type:          docTypeToUse,
+		Shards:        c.CommunicationsConfig.ElasticSearch.Index.Shards,
+		Replicas:      c.CommunicationsConfig.ElasticSearch.Index.Replicas,
+		ClusterName:   c.Settings.ClusterName,
+		ServerVersion: serverVersion,
+		MajorVersion:  majorVersion,
+		UseILM:        ilmCfg.Enabled,
+		WriteAlias:    writeAlias,
+		Formatter:     newDocumentFormatter(c.CommunicationsConfig.ElasticSearch.Format),
 	}, nil
 }
 
+// defaultBulkActions, defaultBulkSize (in bytes), defaultFlushInterval and defaultWorkers mirror
+// olivere/elastic's own BulkProcessor defaults, used whenever the operator leaves them unset.
+const (
+	defaultBulkActions    = 1000
+	defaultBulkSize       = 5 << 20 // 5MB
+	defaultFlushInterval  = 10 * time.Second
+	defaultWorkers        = 1
+	defaultRetryInitialMs = 100
+	defaultRetryMaxMs     = 10000
+)
+
+// newBulkProcessor builds and starts a BulkProcessor so events are batched in the background
+// instead of blocking SendEvent on a synchronous Index+Flush round trip per event.
+func newBulkProcessor(client *elastic.Client, cfg config.ElasticSearch) (*elastic.BulkProcessor, error) {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	bulkActions := cfg.BulkActions
+	if bulkActions <= 0 {
+		bulkActions = defaultBulkActions
+	}
+	bulkSize := cfg.BulkSize
+	if bulkSize <= 0 {
+		bulkSize = defaultBulkSize
+	}
+	flushInterval := defaultFlushInterval
+	if cfg.FlushInterval > 0 {
+		flushInterval = time.Duration(cfg.FlushInterval) * time.Second
+	}
+	initialBackoff := defaultRetryInitialMs
+	if cfg.RetryInitialBackoffMs > 0 {
+		initialBackoff = cfg.RetryInitialBackoffMs
+	}
+	maxBackoff := defaultRetryMaxMs
+	if cfg.RetryMaxBackoffMs > 0 {
+		maxBackoff = cfg.RetryMaxBackoffMs
+	}
+
+	return client.BulkProcessor().
+		Name("botkube-els-bulk-processor").
+		Workers(workers).
+		BulkActions(bulkActions).
+		BulkSize(bulkSize).
+		FlushInterval(flushInterval).
+		// Retries transient 429 (bulk queue full) and 503 (unavailable) responses with backoff.
+		Backoff(elastic.NewExponentialBackoff(time.Duration(initialBackoff)*time.Millisecond, time.Duration(maxBackoff)*time.Millisecond)).
+		// SendEvent returns as soon as a request is enqueued, so this is the only place a
+		// permanently failed commit (retries exhausted, bad mapping, etc.) is ever observed.
+		After(logBulkCommitFailures).
+		Stats(false).
+		Do(context.Background())
+}
+
+// logBulkCommitFailures logs a commit-level error and any per-item failures from a bulk
+// request so events dropped after retries are exhausted aren't lost silently.
+func logBulkCommitFailures(executionID int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if err != nil {
+		log.Error(fmt.Sprintf("ElasticSearch bulk commit %d failed. Error:%s", executionID, err.Error()))
+		return
+	}
+	if response == nil {
+		return
+	}
+	for _, failed := range response.Failed() {
+		log.Error(fmt.Sprintf("ElasticSearch bulk commit %d failed to index document %s/%s. Error:%+v", executionID, failed.Index, failed.Id, failed.Error))
+	}
+}
+
+// buildTLSConfig assembles a tls.Config for mTLS against self-hosted clusters: an optional CA
+// bundle to trust, an optional client cert/key pair, and a dev-only InsecureSkipVerify escape
+// hatch. A nil cfg.CAFile/CertFile leaves the corresponding tls.Config field unset so Go falls
+// back to the system cert pool / no client cert, same as before TLS support was added.
+func buildTLSConfig(cfg config.ElasticSearchTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Elasticsearch CA file %s: %w", cfg.CAFile, err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse Elasticsearch CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	if cfg.CertFile != "" && cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Elasticsearch client cert/key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// authHeaders returns the static headers to send with every request for the auth modes that
+// aren't expressed as a Go http.Client option, namely API-key and bearer-token auth (the
+// standard for Elastic Cloud and 8.x).
+func authHeaders(cfg config.ElasticSearch) http.Header {
+	headers := http.Header{}
+	switch {
+	case cfg.APIKey != "":
+		headers.Set("Authorization", "ApiKey "+cfg.APIKey)
+	case cfg.BearerToken != "":
+		headers.Set("Authorization", "Bearer "+cfg.BearerToken)
+	}
+	return headers
+}
+
+// decodeCloudID decodes an Elastic Cloud ID (format "deployment-name:base64(host$esUUID$kibanaUUID)")
+// into the HTTPS URL of the deployment's Elasticsearch endpoint.
+func decodeCloudID(cloudID string) (string, error) {
+	parts := strings.SplitN(cloudID, ":", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid Elastic Cloud ID %q", cloudID)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode Elastic Cloud ID %q: %w", cloudID, err)
+	}
+	segments := strings.Split(string(decoded), "$")
+	if len(segments) < 2 {
+		return "", fmt.Errorf("invalid Elastic Cloud ID %q", cloudID)
+	}
+	domain, esUUID := segments[0], segments[1]
+	return fmt.Sprintf("https://%s.%s", esUUID, domain), nil
+}
+
+// parseMajorVersion extracts the leading major version number out of an Elasticsearch
+// version string such as "7.10.2".
+func parseMajorVersion(version string) (int, error) {
+	parts := strings.SplitN(version, ".", 2)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse Elasticsearch server version %q: %w", version, err)
+	}
+	return major, nil
+}
+
 type mapping struct {
 	Settings settings `json:"settings"`
 }
@@ -113,49 +398,371 @@ type index struct {
 	Replicas int `json:"number_of_replicas"`
 }
 
+// bootstrapIndexSuffix is appended to the write alias to create the first index the alias
+// points at, e.g. "botkube-events-write-000001". Rollover increments the numeric suffix from there.
+const bootstrapIndexSuffix = "-000001"
+
+// ilmPolicyBody is the subset of an ILM policy document botkube manages: a hot phase that
+// rolls the index over on age/size, a warm phase, and a delete phase, all driven by
+// config.ElasticSearchILM.
+type ilmPolicyBody struct {
+	Policy ilmPolicy `json:"policy"`
+}
+
+type ilmPolicy struct {
+	Phases ilmPhases `json:"phases"`
+}
+
+type ilmPhases struct {
+	Hot    ilmHotPhase  `json:"hot"`
+	Warm   *ilmAgePhase `json:"warm,omitempty"`
+	Delete *ilmAgePhase `json:"delete,omitempty"`
+}
+
+type ilmHotPhase struct {
+	Actions ilmHotActions `json:"actions"`
+}
+
+type ilmHotActions struct {
+	Rollover ilmRollover `json:"rollover"`
+}
+
+type ilmRollover struct {
+	MaxAge  string `json:"max_age,omitempty"`
+	MaxSize string `json:"max_size,omitempty"`
+}
+
+type ilmAgePhase struct {
+	MinAge  string      `json:"min_age"`
+	Actions interface{} `json:"actions"`
+}
+
+// indexTemplateBody is a composable index template (`_index_template`, 7.8+) that points new
+// rollover indices at the write alias and maps events.Event fields so they're searchable and
+// aggregatable in Kibana rather than dynamically mapped.
+type indexTemplateBody struct {
+	IndexPatterns []string              `json:"index_patterns"`
+	Template      indexTemplateSettings `json:"template"`
+}
+
+type indexTemplateSettings struct {
+	Settings indexTemplateIndexSettings `json:"settings"`
+	Mappings indexTemplateMappings      `json:"mappings"`
+}
+
+type indexTemplateIndexSettings struct {
+	NumberOfShards   int    `json:"number_of_shards"`
+	NumberOfReplicas int    `json:"number_of_replicas"`
+	LifecycleName    string `json:"index.lifecycle.name"`
+	RolloverAlias    string `json:"index.lifecycle.rollover_alias"`
+}
+
+type indexTemplateMappings struct {
+	Properties map[string]indexFieldMapping `json:"properties"`
+}
+
+type indexFieldMapping struct {
+	Type       string                       `json:"type,omitempty"`
+	Properties map[string]indexFieldMapping `json:"properties,omitempty"`
+	Enabled    *bool                        `json:"enabled,omitempty"`
+}
+
+// eventFieldMappings returns the field mappings matching the document shape the configured
+// DocumentFormatter actually produces, so the fields are searchable/aggregatable instead of
+// falling through to dynamic mapping.
+func eventFieldMappings(format string) map[string]indexFieldMapping {
+	if strings.EqualFold(format, "ecs") {
+		return ecsEventFieldMappings()
+	}
+	return rawEventFieldMappings()
+}
+
+// rawEventFieldMappings matches rawDocumentFormatter's output: events.Event re-serialized with
+// its unmodified (PascalCase, untagged) Go field names.
+func rawEventFieldMappings() map[string]indexFieldMapping {
+	return map[string]indexFieldMapping{
+		"Cluster":   {Type: "keyword"},
+		"Namespace": {Type: "keyword"},
+		"Kind":      {Type: "keyword"},
+		"Name":      {Type: "keyword"},
+		"TimeStamp": {Type: "date"},
+		"Messages":  {Type: "text"},
+	}
+}
+
+// ecsEventFieldMappings matches ecsDocumentFormatter's output: @timestamp/message at the top
+// level, and event/orchestrator/kubernetes nested objects.
+func ecsEventFieldMappings() map[string]indexFieldMapping {
+	return map[string]indexFieldMapping{
+		"@timestamp": {Type: "date"},
+		"message":    {Type: "text"},
+		"event": {Properties: map[string]indexFieldMapping{
+			"kind":     {Type: "keyword"},
+			"category": {Type: "keyword"},
+			"action":   {Type: "keyword"},
+			"dataset":  {Type: "keyword"},
+		}},
+		"orchestrator": {Properties: map[string]indexFieldMapping{
+			"cluster":   {Properties: map[string]indexFieldMapping{"name": {Type: "keyword"}}},
+			"namespace": {Type: "keyword"},
+			"resource": {Properties: map[string]indexFieldMapping{
+				"type": {Type: "keyword"},
+				"name": {Type: "keyword"},
+			}},
+		}},
+		"kubernetes": {Properties: map[string]indexFieldMapping{
+			"reason": {Type: "keyword"},
+			// Labels/annotations are arbitrary user-defined key/value maps, so their subfields are
+			// left unindexed (enabled: false) rather than dynamically mapped, which would otherwise
+			// blow up the index's field count as different workloads introduce new label keys.
+			"labels":      {Enabled: boolPtr(false)},
+			"annotations": {Enabled: boolPtr(false)},
+		}},
+	}
+}
+
+// boolPtr returns a pointer to b, for struct fields that need to distinguish "false" from "unset".
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// ensureRollover installs the ILM policy and composable index template, then bootstraps the
+// first write index if the write alias doesn't exist yet. format selects which DocumentFormatter
+// shape the index template's field mappings are bound to.
+func ensureRollover(ctx context.Context, client *elastic.Client, cfg config.ElasticSearchILM, writeAlias, format string) error {
+	policyName := cfg.PolicyName
+	if policyName == "" {
+		policyName = "botkube-events-policy"
+	}
+	policy := ilmPolicyBody{
+		Policy: ilmPolicy{
+			Phases: ilmPhases{
+				Hot: ilmHotPhase{
+					Actions: ilmHotActions{
+						Rollover: ilmRollover{
+							MaxAge:  cfg.RolloverMaxAge,
+							MaxSize: cfg.RolloverMaxSize,
+						},
+					},
+				},
+			},
+		},
+	}
+	if cfg.WarmMinAge != "" {
+		policy.Policy.Phases.Warm = &ilmAgePhase{MinAge: cfg.WarmMinAge, Actions: struct{}{}}
+	}
+	if cfg.DeleteMinAge != "" {
+		policy.Policy.Phases.Delete = &ilmAgePhase{MinAge: cfg.DeleteMinAge, Actions: map[string]interface{}{"delete": struct{}{}}}
+	}
+	if _, err := client.XPackIlmPutLifecycle(policyName).BodyJson(policy).Do(ctx); err != nil {
+		return fmt.Errorf("failed to create ILM policy %s: %w", policyName, err)
+	}
+
+	templateName := writeAlias + "-template"
+	template := indexTemplateBody{
+		IndexPatterns: []string{writeAlias + "-*"},
+		Template: indexTemplateSettings{
+			Settings: indexTemplateIndexSettings{
+				NumberOfShards:   1,
+				NumberOfReplicas: 1,
+				LifecycleName:    policyName,
+				RolloverAlias:    writeAlias,
+			},
+			Mappings: indexTemplateMappings{Properties: eventFieldMappings(format)},
+		},
+	}
+	if _, err := client.IndexPutIndexTemplate(templateName).BodyJson(template).Do(ctx); err != nil {
+		return fmt.Errorf("failed to create index template %s: %w", templateName, err)
+	}
+
+	// A clean cluster returns a 404 ("alias_not_found_exception") here on every first-time
+	// bootstrap, since no index holds the write alias yet; that's not an error, it just means
+	// we still need to create the bootstrap index below.
+	aliasResult, err := client.Aliases().Alias(writeAlias).Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
+		return fmt.Errorf("failed to check write alias %s: %w", writeAlias, err)
+	}
+	if aliasResult != nil && len(aliasResult.Indices) > 0 {
+		return nil
+	}
+
+	bootstrapIndex := writeAlias + bootstrapIndexSuffix
+	_, err = client.CreateIndex(bootstrapIndex).BodyJson(map[string]interface{}{
+		"aliases": map[string]interface{}{
+			writeAlias: map[string]interface{}{"is_write_index": true},
+		},
+	}).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap rollover index %s: %w", bootstrapIndex, err)
+	}
+	return nil
+}
+
+// DocumentFormatter turns a botkube event into the document body that gets indexed, letting
+// operators pick a schema that matches their existing Kibana dashboards/ingest pipelines.
+type DocumentFormatter interface {
+	Format(event events.Event) (interface{}, error)
+}
+
+// rawDocumentFormatter indexes events.Event as-is; this is the formatter botkube has always used.
+type rawDocumentFormatter struct{}
+
+// Format returns the event unchanged.
+func (rawDocumentFormatter) Format(event events.Event) (interface{}, error) {
+	return event, nil
+}
+
+// ecsDocumentFormatter transforms events.Event into Elastic Common Schema fields so botkube
+// events sit alongside other observability data in Kibana.
+type ecsDocumentFormatter struct{}
+
+// Format builds an ECS document: @timestamp/event.* describe the change itself,
+// orchestrator.* identifies what it happened to, and kubernetes.* carries the raw metadata.
+// botkube's own event types (create/update/delete/error) already match the ECS event.action
+// vocabulary, so the type is passed through lowercased rather than remapped.
+func (ecsDocumentFormatter) Format(event events.Event) (interface{}, error) {
+	action := strings.ToLower(string(event.Type))
+	return map[string]interface{}{
+		"@timestamp": event.TimeStamp,
+		"event": map[string]interface{}{
+			"kind":     "event",
+			"category": []string{"orchestration"},
+			"action":   action,
+			"dataset":  "botkube.k8s",
+		},
+		"message": strings.Join(event.Messages, " "),
+		"orchestrator": map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"name": event.Cluster,
+			},
+			"namespace": event.Namespace,
+			"resource": map[string]interface{}{
+				"type": event.Kind,
+				"name": event.Name,
+			},
+		},
+		"kubernetes": map[string]interface{}{
+			"labels":      event.Labels,
+			"annotations": event.Annotations,
+			"reason":      event.Reason,
+		},
+	}, nil
+}
+
+// newDocumentFormatter selects the DocumentFormatter for the configured format; an unknown or
+// empty value falls back to "raw" so existing deployments keep their current document shape.
+func newDocumentFormatter(format string) DocumentFormatter {
+	if strings.EqualFold(format, "ecs") {
+		return ecsDocumentFormatter{}
+	}
+	return rawDocumentFormatter{}
+}
+
 // SendEvent sends event notification to slack
 func (e *ElasticSearch) SendEvent(event events.Event) (err error) {
 	log.Debug(fmt.Sprintf(">> Sending to ElasticSearch: %+v", event))
-	ctx := context.Background()
 
 	// set missing cluster name to event object
 	event.Cluster = e.ClusterName
 
-	// Create index if not exists
-	exists, err := e.ELSClient.IndexExists(e.Index + "-" + time.Now().Format(indexSuffixFormat)).Do(ctx)
+	doc, err := e.Formatter.Format(event)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to get index. Error:%s", err.Error()))
+		log.Error(fmt.Sprintf("Failed to format event for ElasticSearch. Error:%s", err.Error()))
 		return err
 	}
-	if !exists {
-		// Create a new index.
-		mapping := mapping{
-			Settings: settings{
-				index{
-					Shards:   e.Shards,
-					Replicas: e.Replicas,
-				},
-			},
-		}
-		_, err := e.ELSClient.CreateIndex(e.Index + "-" + time.Now().Format(indexSuffixFormat)).BodyJson(mapping).Do(ctx)
+
+	if e.MajorVersion >= 8 {
+		return e.sendEventV8(doc)
+	}
+
+	ctx := context.Background()
+	indexName := e.Index + "-" + time.Now().Format(indexSuffixFormat)
+	if e.UseILM {
+		// Rollover mode: always index through the write alias, the index template and ILM
+		// policy installed by ensureRollover take care of creating/rotating the backing index.
+		indexName = e.WriteAlias
+	} else {
+		// Create index if not exists
+		exists, err := e.ELSClient.IndexExists(indexName).Do(ctx)
 		if err != nil {
-			log.Error(fmt.Sprintf("Failed to create index. Error:%s", err.Error()))
+			log.Error(fmt.Sprintf("Failed to get index. Error:%s", err.Error()))
 			return err
 		}
+		if !exists {
+			// Create a new index.
+			mapping := mapping{
+				Settings: settings{
+					index{
+						Shards:   e.Shards,
+						Replicas: e.Replicas,
+					},
+				},
+			}
+			_, err := e.ELSClient.CreateIndex(indexName).BodyJson(mapping).Do(ctx)
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to create index. Error:%s", err.Error()))
+				return err
+			}
+		}
+	}
+
+	// Enqueue the event on the bulk processor instead of indexing+flushing synchronously.
+	// Mapping types were removed in 7.x, so e.Type is always "_doc" there.
+	bulkRequest := elastic.NewBulkIndexRequest().Index(indexName).Doc(doc)
+	if e.MajorVersion < 7 {
+		bulkRequest = bulkRequest.Type(e.Type)
+	} else {
+		bulkRequest = bulkRequest.Type(docType)
+	}
+	e.BulkProcessor.Add(bulkRequest)
+	log.Debugf("Event queued for ElasticSearch index %s", indexName)
+	return nil
+}
+
+// Shutdown flushes any events still queued in the bulk processor and releases its workers.
+// It is invoked by the main loop on SIGTERM so events buffered at shutdown are not lost.
+func (e *ElasticSearch) Shutdown(ctx context.Context) error {
+	if e.BulkProcessor == nil {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- e.BulkProcessor.Close()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
+
+// Close is a convenience wrapper around Shutdown for callers that don't carry a context.
+func (e *ElasticSearch) Close() error {
+	return e.Shutdown(context.Background())
+}
 
-	// Send event to els
-	_, err = e.ELSClient.Index().Index(e.Index + "-" + time.Now().Format(indexSuffixFormat)).Type(e.Type).BodyJson(event).Do(ctx)
+// sendEventV8 indexes the formatted document via the go-elasticsearch/v8 transport, used when
+// talking to an 8.x cluster where olivere/elastic's REST surface is no longer compatible.
+func (e *ElasticSearch) sendEventV8(doc interface{}) error {
+	indexName := e.Index + "-" + time.Now().Format(indexSuffixFormat)
+	body, err := json.Marshal(doc)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to post data to els. Error:%s", err.Error()))
+		log.Error(fmt.Sprintf("Failed to marshal event. Error:%s", err.Error()))
 		return err
 	}
-	_, err = e.ELSClient.Flush().Index(e.Index + "-" + time.Now().Format(indexSuffixFormat)).Do(ctx)
+	res, err := e.ELS8Client.Index(indexName, bytes.NewReader(body))
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to flush data to els. Error:%s", err.Error()))
+		log.Error(fmt.Sprintf("Failed to post data to els. Error:%s", err.Error()))
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		err := fmt.Errorf("elasticsearch returned an error response: %s", res.String())
+		log.Error(fmt.Sprintf("Failed to post data to els. Error:%s", err.Error()))
 		return err
 	}
-	log.Debugf("Event successfully sent to ElasticSearch index %s", e.Index+"-"+time.Now().Format(indexSuffixFormat))
+	log.Debugf("Event successfully sent to ElasticSearch index %s", indexName)
 	return nil
 }
 
@@ -0,0 +1,265 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestParseMajorVersion(t *testing.T) {
+	tests := map[string]struct {
+		version string
+		want    int
+		wantErr bool
+	}{
+		"5.x":         {version: "5.6.16", want: 5},
+		"6.x":         {version: "6.8.23", want: 6},
+		"7.x":         {version: "7.10.2", want: 7},
+		"8.x":         {version: "8.1.0", want: 8},
+		"no dot":      {version: "7", want: 7},
+		"empty":       {version: "", wantErr: true},
+		"non-numeric": {version: "abc.1.2", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseMajorVersion(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseMajorVersion(%q) expected an error, got nil", tt.version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMajorVersion(%q) returned unexpected error: %v", tt.version, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseMajorVersion(%q) = %d, want %d", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCloudID(t *testing.T) {
+	tests := map[string]struct {
+		cloudID string
+		want    string
+		wantErr bool
+	}{
+		// base64("my-deployment.es.io$abc123$def456")
+		"valid": {
+			cloudID: "my-deployment:bXktZGVwbG95bWVudC5lcy5pbyRhYmMxMjMkZGVmNDU2",
+			want:    "https://abc123.my-deployment.es.io",
+		},
+		"missing colon": {
+			cloudID: "not-a-cloud-id",
+			wantErr: true,
+		},
+		"invalid base64": {
+			cloudID: "name:not-base64!!!",
+			wantErr: true,
+		},
+		"missing segments": {
+			// base64("just-a-domain")
+			cloudID: "name:anVzdC1hLWRvbWFpbg==",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := decodeCloudID(tt.cloudID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeCloudID(%q) expected an error, got nil", tt.cloudID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCloudID(%q) returned unexpected error: %v", tt.cloudID, err)
+			}
+			if got != tt.want {
+				t.Errorf("decodeCloudID(%q) = %q, want %q", tt.cloudID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	t.Run("empty config leaves defaults", func(t *testing.T) {
+		tlsConfig, err := buildTLSConfig(config.ElasticSearchTLS{})
+		if err != nil {
+			t.Fatalf("buildTLSConfig returned unexpected error: %v", err)
+		}
+		if tlsConfig.InsecureSkipVerify {
+			t.Error("expected InsecureSkipVerify to default to false")
+		}
+		if tlsConfig.RootCAs != nil {
+			t.Error("expected RootCAs to be unset without a CAFile")
+		}
+	})
+
+	t.Run("insecure skip verify is honored", func(t *testing.T) {
+		tlsConfig, err := buildTLSConfig(config.ElasticSearchTLS{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatalf("buildTLSConfig returned unexpected error: %v", err)
+		}
+		if !tlsConfig.InsecureSkipVerify {
+			t.Error("expected InsecureSkipVerify to be true")
+		}
+	})
+
+	t.Run("missing CA file errors", func(t *testing.T) {
+		_, err := buildTLSConfig(config.ElasticSearchTLS{CAFile: "/nonexistent/ca.pem"})
+		if err == nil {
+			t.Fatal("expected an error for a missing CA file, got nil")
+		}
+	})
+
+	t.Run("missing cert file errors", func(t *testing.T) {
+		_, err := buildTLSConfig(config.ElasticSearchTLS{CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"})
+		if err == nil {
+			t.Fatal("expected an error for a missing client cert, got nil")
+		}
+	})
+}
+
+func TestAuthHeaders(t *testing.T) {
+	tests := map[string]struct {
+		cfg  config.ElasticSearch
+		want string
+	}{
+		"no auth":      {cfg: config.ElasticSearch{}, want: ""},
+		"api key":      {cfg: config.ElasticSearch{APIKey: "my-key"}, want: "ApiKey my-key"},
+		"bearer token": {cfg: config.ElasticSearch{BearerToken: "my-token"}, want: "Bearer my-token"},
+		"api key wins": {cfg: config.ElasticSearch{APIKey: "my-key", BearerToken: "my-token"}, want: "ApiKey my-key"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := authHeaders(tt.cfg).Get("Authorization")
+			if got != tt.want {
+				t.Errorf("authHeaders(%+v).Get(Authorization) = %q, want %q", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDocumentFormatter(t *testing.T) {
+	tests := map[string]struct {
+		format string
+		want   DocumentFormatter
+	}{
+		"empty defaults to raw":   {format: "", want: rawDocumentFormatter{}},
+		"raw":                     {format: "raw", want: rawDocumentFormatter{}},
+		"ecs":                     {format: "ecs", want: ecsDocumentFormatter{}},
+		"ECS is case-insensitive": {format: "ECS", want: ecsDocumentFormatter{}},
+		"unknown defaults to raw": {format: "unknown", want: rawDocumentFormatter{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := newDocumentFormatter(tt.format); got != tt.want {
+				t.Errorf("newDocumentFormatter(%q) = %T, want %T", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEcsDocumentFormatterFormat(t *testing.T) {
+	timestamp := time.Date(2026, 7, 29, 12, 0, 0, 0, time.UTC)
+	event := events.Event{
+		Cluster:   "test-cluster",
+		Namespace: "default",
+		Kind:      "Pod",
+		Name:      "my-pod",
+		Type:      "create",
+		Reason:    "Scheduled",
+		Messages:  []string{"Pod scheduled"},
+		TimeStamp: timestamp,
+	}
+
+	doc, err := ecsDocumentFormatter{}.Format(event)
+	if err != nil {
+		t.Fatalf("Format returned unexpected error: %v", err)
+	}
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Format returned %T, want map[string]interface{}", doc)
+	}
+
+	if fields["@timestamp"] != timestamp {
+		t.Errorf("@timestamp = %v, want %v", fields["@timestamp"], timestamp)
+	}
+	if fields["message"] != "Pod scheduled" {
+		t.Errorf("message = %v, want %q", fields["message"], "Pod scheduled")
+	}
+
+	eventFields, ok := fields["event"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("event field = %T, want map[string]interface{}", fields["event"])
+	}
+	if eventFields["action"] != "create" {
+		t.Errorf("event.action = %v, want %q", eventFields["action"], "create")
+	}
+
+	orchestrator, ok := fields["orchestrator"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("orchestrator field = %T, want map[string]interface{}", fields["orchestrator"])
+	}
+	if orchestrator["namespace"] != "default" {
+		t.Errorf("orchestrator.namespace = %v, want %q", orchestrator["namespace"], "default")
+	}
+}
+
+func TestEventFieldMappings(t *testing.T) {
+	t.Run("raw format maps the untagged Go field names", func(t *testing.T) {
+		mappings := eventFieldMappings("raw")
+		for _, field := range []string{"Cluster", "Namespace", "Kind", "Name", "TimeStamp", "Messages"} {
+			if _, ok := mappings[field]; !ok {
+				t.Errorf("expected raw mapping to contain field %q", field)
+			}
+		}
+	})
+
+	t.Run("ecs format maps the ECS document shape", func(t *testing.T) {
+		mappings := eventFieldMappings("ecs")
+		for _, field := range []string{"@timestamp", "message", "event", "orchestrator", "kubernetes"} {
+			if _, ok := mappings[field]; !ok {
+				t.Errorf("expected ecs mapping to contain field %q", field)
+			}
+		}
+		orchestrator, ok := mappings["orchestrator"]
+		if !ok || orchestrator.Properties["namespace"].Type != "keyword" {
+			t.Error("expected orchestrator.namespace to be mapped as keyword")
+		}
+
+		kubernetes, ok := mappings["kubernetes"]
+		if !ok || kubernetes.Properties["reason"].Type != "keyword" {
+			t.Error("expected kubernetes.reason to be mapped as keyword")
+		}
+		for _, field := range []string{"labels", "annotations"} {
+			prop, ok := kubernetes.Properties[field]
+			if !ok || prop.Enabled == nil || *prop.Enabled {
+				t.Errorf("expected kubernetes.%s to be mapped with enabled: false", field)
+			}
+		}
+	})
+}